@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultMaxBufferSize = 1024
+	defaultFlushInterval = 5 * time.Second
+)
+
+// enqueue buffers rec for the background goroutine to write, unless its
+// severity is at or above the configured sync threshold, in which case
+// it is written synchronously so the record isn't lost on crash.
+func (l *Logger) enqueue(rec Record) {
+	if rec.Severity >= Severity(atomic.LoadInt32(&l.syncSeverity)) {
+		l.syncWG.Add(1)
+		defer l.syncWG.Done()
+		l.writeRecord(rec)
+		return
+	}
+
+	l.bufMu.Lock()
+	l.pending = append(l.pending, rec)
+	overflow := l.maxBufferSize > 0 && len(l.pending) > l.maxBufferSize
+	l.bufMu.Unlock()
+
+	if overflow {
+		l.Flush()
+	}
+}
+
+// run drains the pending buffer on every tick of interval, on an
+// explicit Flush request, and once more before exiting when stop is
+// closed.
+func (l *Logger) run(interval time.Duration) {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			l.drain()
+			return
+		case d := <-l.intervalCh:
+			ticker.Reset(d)
+		case ack := <-l.flushReq:
+			l.drain()
+			close(ack)
+		case <-ticker.C:
+			l.drain()
+		}
+	}
+}
+
+func (l *Logger) drain() {
+	l.bufMu.Lock()
+	pending := l.pending
+	l.pending = nil
+	l.bufMu.Unlock()
+
+	for _, rec := range pending {
+		l.writeRecord(rec)
+	}
+}
+
+// writeRecord routes rec to its sinks, recovering from a panic in any
+// one of them so a bad downstream sink can't take down the flush
+// goroutine and deadlock producers waiting on Flush.
+func (l *Logger) writeRecord(rec Record) {
+	for _, sink := range l.sinksFor(rec) {
+		l.writeToSink(sink, rec)
+	}
+}
+
+func (l *Logger) writeToSink(sink Sink, rec Record) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "logger: panic writing log record: %v\n", r)
+		}
+	}()
+	if err := sink.Write(rec); err != nil {
+		fmt.Fprintf(os.Stderr, "logger: sink write error: %v\n", err)
+	}
+}
+
+// Flush blocks until all currently buffered records have been written.
+func (l *Logger) Flush() {
+	ack := make(chan struct{})
+	select {
+	case l.flushReq <- ack:
+		<-ack
+	case <-l.stop:
+	}
+}
+
+// Flush flushes the default logger's pending buffered records.
+func Flush() {
+	defaultLogger.Flush()
+}
+
+// SetFlushInterval sets how often buffered records are flushed.
+func (l *Logger) SetFlushInterval(d time.Duration) {
+	select {
+	case l.intervalCh <- d:
+	case <-l.stop:
+	}
+}
+
+// SetFlushInterval sets how often the default logger flushes buffered
+// records. The default is 5s.
+func SetFlushInterval(d time.Duration) {
+	defaultLogger.SetFlushInterval(d)
+}
+
+// SetMaxBufferSize bounds the number of buffered records; once exceeded,
+// the buffer is flushed immediately rather than growing further. A value
+// of 0 disables the bound.
+func (l *Logger) SetMaxBufferSize(n int) {
+	l.bufMu.Lock()
+	l.maxBufferSize = n
+	l.bufMu.Unlock()
+}
+
+// SetMaxBufferSize bounds the default logger's buffered record count.
+// The default is 1024.
+func SetMaxBufferSize(n int) {
+	defaultLogger.SetMaxBufferSize(n)
+}
+
+// SetSyncSeverity sets the severity at or above which records bypass
+// buffering and are written synchronously. The default is SeverityError.
+func (l *Logger) SetSyncSeverity(s Severity) {
+	atomic.StoreInt32(&l.syncSeverity, int32(s))
+}
+
+// SetSyncSeverity sets the severity threshold for the default logger.
+func SetSyncSeverity(s Severity) {
+	defaultLogger.SetSyncSeverity(s)
+}
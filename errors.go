@@ -0,0 +1,42 @@
+package logger
+
+import "errors"
+
+// messager is implemented by errors that distinguish their own message
+// from the full chain rendered by Error(), such as *errorx.Error.
+type messager interface {
+	Message() string
+}
+
+// stackTracer is implemented by errors that captured a stack trace,
+// such as *errorx.Error.
+type stackTracer interface {
+	Stack() string
+}
+
+// LogError walks err's cause chain, logging each link's own message at
+// Error severity (via Message() where a link provides one, else
+// Error()), then the stack trace of the deepest link that provides one.
+// It's meant for one-call logging of a rich error from e.g. the errorx
+// package, without logger needing to import it.
+func LogError(err error) {
+	if err == nil {
+		return
+	}
+
+	var deepestStack string
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		msg := cur.Error()
+		if m, ok := cur.(messager); ok {
+			msg = m.Message()
+		}
+		defaultLogger.output(SeverityError, 0, nil, msg)
+
+		if st, ok := cur.(stackTracer); ok {
+			deepestStack = st.Stack()
+		}
+	}
+	if deepestStack != "" {
+		defaultLogger.output(SeverityError, 0, nil, deepestStack)
+	}
+}
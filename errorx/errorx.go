@@ -0,0 +1,185 @@
+// Package errorx is a rich error-wrapping layer: errors carry an
+// HTTP-style status code, an optional cause, arbitrary key/value
+// context, and a stack trace captured once where they were created.
+//
+//	if err := step(); err != nil {
+//		errx := errorx.New(http.StatusInternalServerError, "operation failed")
+//		return errx.Wrap(err)
+//	}
+package errorx
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+)
+
+// Error is a wrappable error implementing Unwrap/Is/As so it
+// interoperates with the standard errors package.
+type Error struct {
+	code   int
+	msg    string
+	cause  error
+	fields map[string]interface{}
+	frames []uintptr
+}
+
+// New returns a root *Error with the given HTTP-style status code and
+// message, capturing the current stack trace.
+func New(code int, msg string) *Error {
+	return &Error{
+		code:   code,
+		msg:    msg,
+		frames: callers(),
+	}
+}
+
+// Wrap returns a copy of e with err set as its cause, so the copy's
+// Error(), Unwrap(), etc. report the chain err <- e; e itself is left
+// unchanged. Wrap returns a nil *Error if err is nil; returned through
+// an error-typed result, that nil pointer is a non-nil error value.
+func (e *Error) Wrap(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	w := *e
+	w.cause = err
+	return &w
+}
+
+// WithField returns a copy of e carrying k=v in addition to any fields
+// already on e. WithField is a no-op returning nil on a nil receiver, so
+// it stays safe to chain directly off Wrap: `errx.Wrap(err).WithField(k, v)`.
+func (e *Error) WithField(k string, v interface{}) *Error {
+	if e == nil {
+		return nil
+	}
+	w := *e
+	w.fields = make(map[string]interface{}, len(e.fields)+1)
+	for fk, fv := range e.fields {
+		w.fields[fk] = fv
+	}
+	w.fields[k] = v
+	return &w
+}
+
+// Code returns e's HTTP-style status code, or 0 on a nil receiver.
+func (e *Error) Code() int {
+	if e == nil {
+		return 0
+	}
+	return e.code
+}
+
+// Fields returns e's accumulated key/value context, or nil on a nil receiver.
+func (e *Error) Fields() map[string]interface{} {
+	if e == nil {
+		return nil
+	}
+	return e.fields
+}
+
+// Message returns e's own message, without any wrapped cause appended,
+// or "" on a nil receiver.
+func (e *Error) Message() string {
+	if e == nil {
+		return ""
+	}
+	return e.msg
+}
+
+// Error implements error, rendering the full message chain, e.g.
+// "operation failed: connection refused". A nil receiver renders as "",
+// matching a nil *Error satisfying the error interface with no message.
+func (e *Error) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.cause != nil {
+		return e.msg + ": " + e.cause.Error()
+	}
+	return e.msg
+}
+
+// Unwrap returns e's cause, so errors.Is/errors.As can walk past e. A nil
+// receiver unwraps to nil, ending the chain.
+func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.cause
+}
+
+// Is reports whether target is an *Error with the same Code, so callers
+// can match on status rather than on a specific instance, e.g.
+// errors.Is(err, errorx.New(http.StatusNotFound, "")).
+func (e *Error) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.code == t.code
+}
+
+// As sets *target to e when target is a **Error, so errors.As can
+// recover the first *Error in a chain. A nil receiver never matches.
+func (e *Error) As(target interface{}) bool {
+	if e == nil {
+		return false
+	}
+	t, ok := target.(**Error)
+	if !ok {
+		return false
+	}
+	*t = e
+	return true
+}
+
+// Format implements fmt.Formatter. %v and %s print the message chain,
+// the same as Error(); %+v additionally appends the stack trace
+// captured where e was created.
+func (e *Error) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		io.WriteString(f, e.Error())
+		if f.Flag('+') {
+			io.WriteString(f, "\n")
+			io.WriteString(f, e.Stack())
+		}
+	case 's':
+		io.WriteString(f, e.Error())
+	case 'q':
+		fmt.Fprintf(f, "%q", e.Error())
+	}
+}
+
+// Stack renders the stack trace captured where e was created, one frame
+// per line, or "" on a nil receiver.
+func (e *Error) Stack() string {
+	if e == nil {
+		return ""
+	}
+	frames := runtime.CallersFrames(e.frames)
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// callers captures the stack at the call site of New, skipping the
+// runtime.Callers/callers/New frames themselves.
+func callers() []uintptr {
+	const maxDepth = 32
+	var pcs [maxDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	return pcs[:n]
+}
@@ -0,0 +1,81 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWrapChainsCause(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := New(500, "operation failed").Wrap(cause)
+
+	if got, want := err.Error(), "operation failed: connection refused"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, cause) {
+		t.Fatalf("errors.Is should walk Unwrap to find cause")
+	}
+}
+
+func TestWrapNilIsNoop(t *testing.T) {
+	if err := New(500, "x").Wrap(nil); err != nil {
+		t.Fatalf("Wrap(nil) = %v, want nil", err)
+	}
+}
+
+func TestIsMatchesByCodeAcrossChain(t *testing.T) {
+	notFound := New(404, "missing")
+	err := New(500, "failed").Wrap(notFound)
+
+	if !errors.Is(err, New(404, "")) {
+		t.Fatalf("errors.Is should match by Code against a wrapped cause")
+	}
+	if errors.Is(err, New(403, "")) {
+		t.Fatalf("errors.Is should not match a different Code")
+	}
+}
+
+func TestAsRecoversErrorFromChain(t *testing.T) {
+	root := New(404, "missing")
+	err := fmt.Errorf("wrapped: %w", root)
+
+	var target *Error
+	if !errors.As(err, &target) {
+		t.Fatalf("errors.As should recover the *Error in the chain")
+	}
+	if target.Code() != 404 {
+		t.Fatalf("recovered error Code = %d, want 404", target.Code())
+	}
+}
+
+func TestFormatPlusVIncludesStack(t *testing.T) {
+	err := New(500, "boom")
+
+	if got := fmt.Sprintf("%v", err); got != "boom" {
+		t.Fatalf("%%v = %q, want %q", got, "boom")
+	}
+	if verbose := fmt.Sprintf("%+v", err); !strings.Contains(verbose, "TestFormatPlusVIncludesStack") {
+		t.Fatalf("%%+v should include a stack frame naming this test, got %q", verbose)
+	}
+}
+
+func TestWrapNilThenWithFieldDoesNotPanic(t *testing.T) {
+	err := New(500, "boom").Wrap(nil).WithField("k", "v")
+	if err != nil {
+		t.Fatalf("Wrap(nil).WithField(...) = %v, want nil", err)
+	}
+}
+
+func TestWithFieldDoesNotMutateReceiver(t *testing.T) {
+	base := New(500, "boom").WithField("a", 1)
+	child := base.WithField("b", 2)
+
+	if _, ok := base.Fields()["b"]; ok {
+		t.Fatalf("WithField must not mutate the receiver's fields")
+	}
+	if child.Fields()["a"] != 1 || child.Fields()["b"] != 2 {
+		t.Fatalf("child error missing merged fields: %+v", child.Fields())
+	}
+}
@@ -3,24 +3,38 @@ package logger
 import (
 	"fmt"
 	"io"
-	"log"
 	"os"
-	"runtime/debug"
+	"runtime"
 	"sync"
+	"time"
 )
 
-type severity int
+// Severity indicates the importance of a log record.
+type Severity int
 
+// Severity levels, in increasing order of importance.
 const (
-	sInfo severity = iota
-	sWarning
-	sError
-	sFatal
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+	SeverityFatal
 )
 
-const (
-	flags = log.Ldate | log.Ltime | log.Lshortfile
-)
+// String returns the severity's upper-case name, as used by TextEncoding.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "INFO"
+	case SeverityWarning:
+		return "WARNING"
+	case SeverityError:
+		return "ERROR"
+	case SeverityFatal:
+		return "FATAL"
+	default:
+		return "UNKNOWN"
+	}
+}
 
 var (
 	logLock       sync.Mutex
@@ -29,139 +43,243 @@ var (
 
 // Logger ...
 type Logger struct {
-	infoLog     *log.Logger
-	warningLog  *log.Logger
-	errorLog    *log.Logger
-	fatalLog    *log.Logger
-	closers     []io.Closer
+	sinks       [4][]Sink
+	route       func(Record) []Sink
+	closeSinks  []Sink
+	encodings   [4]Encoding
 	initialized bool
+
+	bufMu         sync.Mutex
+	pending       []Record
+	maxBufferSize int
+	syncSeverity  int32 // atomic Severity; records at or above this bypass buffering
+
+	flushReq   chan chan struct{}
+	intervalCh chan time.Duration
+	stop       chan struct{}
+	wg         sync.WaitGroup
+	syncWG     sync.WaitGroup // in-flight synchronous (sync-severity) writes
+	closeOnce  sync.Once
 }
 
-func (l *Logger) output(s severity, depth int, txt string) {
-	logLock.Lock()
-	defer logLock.Unlock()
-	switch s {
-	case sInfo:
-		l.infoLog.Output(3+depth, txt)
-	case sWarning:
-		l.warningLog.Output(3+depth, txt)
-	case sError:
-		l.errorLog.Output(3+depth, txt)
-	case sFatal:
-		l.fatalLog.Output(3+depth, txt)
-	default:
+// Option configures a Logger at Init time.
+type Option func(*Logger)
+
+// WithInfoEncoding sets the encoding used for INFO records.
+func WithInfoEncoding(e Encoding) Option {
+	return func(l *Logger) { l.encodings[SeverityInfo] = e }
+}
+
+// WithWarningEncoding sets the encoding used for WARNING records.
+func WithWarningEncoding(e Encoding) Option {
+	return func(l *Logger) { l.encodings[SeverityWarning] = e }
+}
+
+// WithErrorEncoding sets the encoding used for ERROR records.
+func WithErrorEncoding(e Encoding) Option {
+	return func(l *Logger) { l.encodings[SeverityError] = e }
+}
+
+// WithFatalEncoding sets the encoding used for FATAL records.
+func WithFatalEncoding(e Encoding) Option {
+	return func(l *Logger) { l.encodings[SeverityFatal] = e }
+}
+
+// output builds a Record for txt/fields at the given severity and routes
+// it to the severity's sinks. depth is the number of extra stack frames
+// between the public API call the caller made and output itself, so the
+// reported file:line points at the user's call site rather than a
+// wrapper.
+func (l *Logger) output(s Severity, depth int, fields map[string]interface{}, txt string) {
+	_, file, line, ok := runtime.Caller(2 + depth)
+	if !ok {
+		file = "???"
+		line = 0
+	}
+	if s < SeverityInfo || s > SeverityFatal {
 		panic(fmt.Sprintln("unrecognized severity: ", s))
 	}
+
+	l.enqueue(Record{
+		Time:     time.Now(),
+		Severity: s,
+		File:     file,
+		Line:     line,
+		Message:  txt,
+		Fields:   fields,
+	})
+}
+
+// sinksFor returns the sinks that should receive rec: the result of the
+// routing function if one is configured, otherwise the sinks registered
+// for rec.Severity.
+func (l *Logger) sinksFor(rec Record) []Sink {
+	if l.route != nil {
+		return l.route(rec)
+	}
+	return l.sinks[rec.Severity]
 }
 
 // Info ...
 func Info(v ...interface{}) {
-	defaultLogger.output(sInfo, 0, fmt.Sprint(v...))
+	defaultLogger.output(SeverityInfo, 0, nil, fmt.Sprint(v...))
 }
 
 // Infof ...
 func Infof(format string, v ...interface{}) {
-	defaultLogger.output(sInfo, 0, fmt.Sprintf(format, v...))
+	defaultLogger.output(SeverityInfo, 0, nil, fmt.Sprintf(format, v...))
 }
 
 // Warning ...
 func Warning(v ...interface{}) {
-	defaultLogger.output(sWarning, 0, fmt.Sprint(v...))
+	defaultLogger.output(SeverityWarning, 0, nil, fmt.Sprint(v...))
 }
 
 // Warningf ...
 func Warningf(format string, v ...interface{}) {
-	defaultLogger.output(sWarning, 0, fmt.Sprintf(format, v...))
+	defaultLogger.output(SeverityWarning, 0, nil, fmt.Sprintf(format, v...))
 }
 
 // Error ...
 func Error(v ...interface{}) {
-	defaultLogger.output(sError, 0, fmt.Sprint(v...))
+	defaultLogger.output(SeverityError, 0, nil, fmt.Sprint(v...))
 }
 
 // Errorf ...
 func Errorf(format string, v ...interface{}) {
-	defaultLogger.output(sError, 0, fmt.Sprintf(format, v...))
+	defaultLogger.output(SeverityError, 0, nil, fmt.Sprintf(format, v...))
 }
 
 // Fatal ...
 func Fatal(v ...interface{}) {
-	defaultLogger.output(sFatal, 0, fmt.Sprint(v...))
+	defaultLogger.output(SeverityFatal, 0, nil, fmt.Sprint(v...))
+	defaultLogger.Flush()
 	defaultLogger.Close()
 	os.Exit(1)
 }
 
 // Fatalf ...
 func Fatalf(format string, v ...interface{}) {
-	defaultLogger.output(sFatal, 0, fmt.Sprintf(format, v...))
+	defaultLogger.output(SeverityFatal, 0, nil, fmt.Sprintf(format, v...))
+	defaultLogger.Flush()
 	defaultLogger.Close()
 	os.Exit(1)
 }
 
-// Close ...
+// Close stops the background flush goroutine, draining any pending
+// records, waits for any synchronous (sync-severity) writes already in
+// flight to finish, then closes the registered sinks. This ordering
+// lets a synchronous Error/Fatal write that is already in progress
+// finish before its sink is closed; a write begun after that wait is
+// not held back and may reach a closed sink.
 func (l *Logger) Close() {
+	l.closeOnce.Do(func() {
+		close(l.stop)
+		l.wg.Wait()
+	})
+
+	l.syncWG.Wait()
+
 	logLock.Lock()
 	defer logLock.Unlock()
-	for _, c := range l.closers {
-		if err := c.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to close log %v: %v\n", c, err)
+	for _, s := range l.closeSinks {
+		if err := s.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to close log sink %v: %v\n", s, err)
 		}
 	}
 }
 
-// Init ...
-func Init(infoHandle io.Writer,
-	warningHandle io.Writer,
-	errorHandle io.Writer,
-	fatalHandle io.Writer) {
-	defaultLogger = &Logger{
-		infoLog:    log.New(infoHandle, "INFO: ", flags),
-		warningLog: log.New(warningHandle, "WARNING: ", flags),
-		errorLog:   log.New(errorHandle, "ERROR: ", flags),
-		fatalLog:   log.New(fatalHandle, "FATAL: ", flags),
+// newLogger allocates a Logger with its buffering machinery wired up but
+// no sinks configured yet; callers populate sinks/route before starting
+// the flush goroutine with start.
+func newLogger(opts ...Option) *Logger {
+	l := &Logger{
+		initialized:   true,
+		maxBufferSize: defaultMaxBufferSize,
+		syncSeverity:  int32(SeverityError),
+		flushReq:      make(chan chan struct{}),
+		intervalCh:    make(chan time.Duration, 1),
+		stop:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
 	}
+	return l
 }
 
-// FileForSaving ...
-func FileForSaving(fileName string) *os.File {
-	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
-		log.Fatalln("Failed to open log file", file, ":", err)
-	}
-	return file
+func (l *Logger) start() {
+	l.wg.Add(1)
+	go l.run(defaultFlushInterval)
 }
 
-// CustomError ..
-type CustomError struct {
-	Message    string
-	StackTrace string
+// Init preserves the logger's original io.Writer-based API as a thin
+// adapter: each handle is wrapped in a WriterSink (using the encoding
+// set via WithInfoEncoding et al., TextEncoding by default) so existing
+// callers keep working unchanged. For explicit sinks or custom routing,
+// use InitSinks or InitRoute instead.
+func Init(infoHandle io.Writer,
+	warningHandle io.Writer,
+	errorHandle io.Writer,
+	fatalHandle io.Writer,
+	opts ...Option) {
+	l := newLogger(opts...)
+	l.sinks[SeverityInfo] = []Sink{NewWriterSink(infoHandle, l.encodings[SeverityInfo])}
+	l.sinks[SeverityWarning] = []Sink{NewWriterSink(warningHandle, l.encodings[SeverityWarning])}
+	l.sinks[SeverityError] = []Sink{NewWriterSink(errorHandle, l.encodings[SeverityError])}
+	l.sinks[SeverityFatal] = []Sink{NewWriterSink(fatalHandle, l.encodings[SeverityFatal])}
+	l.closeSinks = dedupeSinks(l.sinks[SeverityInfo], l.sinks[SeverityWarning], l.sinks[SeverityError], l.sinks[SeverityFatal])
+	l.start()
+	defaultLogger = l
 }
 
-// ErrorStruct presents custom errors
-type ErrorStruct struct {
-	CustomError
+// InitSinks configures the default logger with explicit sinks per
+// severity; a severity may have zero, one, or several sinks. All sinks
+// passed in are closed (once each, by identity) on Close.
+func InitSinks(infoSinks, warningSinks, errorSinks, fatalSinks []Sink, opts ...Option) {
+	l := newLogger(opts...)
+	l.sinks[SeverityInfo] = infoSinks
+	l.sinks[SeverityWarning] = warningSinks
+	l.sinks[SeverityError] = errorSinks
+	l.sinks[SeverityFatal] = fatalSinks
+	l.closeSinks = dedupeSinks(infoSinks, warningSinks, errorSinks, fatalSinks)
+	l.start()
+	defaultLogger = l
 }
 
-// WrapError return custom error struct
-func WrapError(messagef string) CustomError {
-	return CustomError{
-		Message:    messagef,
-		StackTrace: string(debug.Stack()),
-	}
+// InitRoute configures the default logger with a routing function that
+// picks the sinks for each record, overriding per-severity routing
+// entirely. allSinks lists every sink route may ever return, so Close
+// can close each of them exactly once.
+func InitRoute(route func(Record) []Sink, allSinks []Sink, opts ...Option) {
+	l := newLogger(opts...)
+	l.route = route
+	l.closeSinks = dedupeSinks(allSinks)
+	l.start()
+	defaultLogger = l
 }
 
-// Return ...
-func Return(err error) error {
-	return ErrorStruct{CustomError: WrapError(err.Error())}
+func dedupeSinks(lists ...[]Sink) []Sink {
+	seen := make(map[Sink]bool)
+	var out []Sink
+	for _, list := range lists {
+		for _, s := range list {
+			if s == nil || seen[s] {
+				continue
+			}
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
 }
 
-// Need implement issue, you can do not use it
-func (err CustomError) Error() string {
-	return err.Message
+// FileForSaving ...
+func FileForSaving(fileName string) *os.File {
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to open log file", file, ":", err)
+		os.Exit(1)
+	}
+	return file
 }
 
-// Example
-// if err := step(); err != nil {
-//   errx := errorx.New(http.StatusInternalServerError, "Operation failed")
-//   errx.Wrap(err)
-// }
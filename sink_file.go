@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileSink writes records to a file, rotating it before a write that
+// would take it past MaxSize bytes or once MaxAge elapses since it was
+// opened, and keeping at
+// most MaxBackups rotated files around (0 keeps them all). On platforms
+// with SIGHUP (everything but Windows), sending the process SIGHUP also
+// reopens the file, for logrotate compatibility.
+type FileSink struct {
+	Path       string
+	Enc        Encoding
+	MaxSize    int64
+	MaxAge     time.Duration
+	MaxBackups int
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	sighup chan os.Signal
+	done   chan struct{}
+}
+
+// NewFileSink opens path for appending, rotating by size and/or age as
+// configured, and starts watching for SIGHUP where the platform
+// supports it.
+func NewFileSink(path string, enc Encoding, maxSize int64, maxAge time.Duration, maxBackups int) (*FileSink, error) {
+	s := &FileSink{
+		Path:       path,
+		Enc:        enc,
+		MaxSize:    maxSize,
+		MaxAge:     maxAge,
+		MaxBackups: maxBackups,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	if sig := hangupSignal(); sig != nil {
+		s.sighup = make(chan os.Signal, 1)
+		s.done = make(chan struct{})
+		signal.Notify(s.sighup, sig)
+		go s.watchSighup()
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.file = f
+	s.size = info.Size()
+	s.openedAt = time.Now()
+	return nil
+}
+
+func (s *FileSink) watchSighup() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.sighup:
+			s.mu.Lock()
+			s.file.Close()
+			if err := s.open(); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: failed to reopen %s after SIGHUP: %v\n", s.Path, err)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// Write implements Sink.
+func (s *FileSink) Write(rec Record) error {
+	var b []byte
+	if s.Enc == JSONEncoding {
+		b = encodeJSON(rec)
+	} else {
+		b = encodeText(rec)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shouldRotate(len(b)) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(b)
+	s.size += int64(n)
+	return err
+}
+
+func (s *FileSink) shouldRotate(next int) bool {
+	if s.MaxSize > 0 && s.size+int64(next) > s.MaxSize {
+		return true
+	}
+	if s.MaxAge > 0 && time.Since(s.openedAt) > s.MaxAge {
+		return true
+	}
+	return false
+}
+
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	rotated := fmt.Sprintf("%s.%s", s.Path, time.Now().Format("20060102T150405.000000000"))
+	if err := os.Rename(s.Path, rotated); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+	return s.pruneBackups()
+}
+
+func (s *FileSink) pruneBackups() error {
+	if s.MaxBackups <= 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(s.Path + ".*")
+	if err != nil {
+		return err
+	}
+	if len(matches) <= s.MaxBackups {
+		return nil
+	}
+	sort.Strings(matches) // the timestamp suffix sorts chronologically
+	for _, old := range matches[:len(matches)-s.MaxBackups] {
+		if err := os.Remove(old); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Sink.
+func (s *FileSink) Close() error {
+	if s.sighup != nil {
+		signal.Stop(s.sighup)
+		close(s.done)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
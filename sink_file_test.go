@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSinkRotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log")
+
+	s, err := NewFileSink(path, TextEncoding, 10, 0, 0)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer s.Close()
+
+	for i := 0; i < 5; i++ {
+		if err := s.Write(Record{Severity: SeverityInfo, Message: "0123456789"}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) == 0 {
+		t.Fatalf("expected Write past MaxSize to rotate the file, found no rotated files")
+	}
+}
+
+func TestFileSinkPrunesBackupsBeyondMaxBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log")
+
+	s, err := NewFileSink(path, TextEncoding, 10, 0, 2)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer s.Close()
+
+	for i := 0; i < 20; i++ {
+		if err := s.Write(Record{Severity: SeverityInfo, Message: "0123456789"}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) > 2 {
+		t.Fatalf("expected at most MaxBackups=2 rotated files retained, got %d: %v", len(matches), matches)
+	}
+}
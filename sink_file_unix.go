@@ -0,0 +1,14 @@
+//go:build !windows
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// hangupSignal is the signal FileSink watches to trigger a logrotate-style
+// reopen.
+func hangupSignal() os.Signal {
+	return syscall.SIGHUP
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package logger
+
+import "os"
+
+// hangupSignal returns nil on Windows, which has no SIGHUP; FileSink
+// simply doesn't watch for an external reopen signal there.
+func hangupSignal() os.Signal {
+	return nil
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// NetworkSink batches records and ships them to a TCP or UDP endpoint,
+// flushing the batch every FlushInterval or once it reaches BatchSize
+// records, whichever comes first. If the connection drops, it's
+// redialed with exponential backoff (starting at 100ms, capped at
+// MaxBackoff) on the next flush.
+type NetworkSink struct {
+	Network       string // "tcp" or "udp"
+	Addr          string
+	Enc           Encoding
+	BatchSize     int
+	FlushInterval time.Duration
+	MaxBackoff    time.Duration
+
+	mu          sync.Mutex
+	conn        net.Conn
+	batch       [][]byte
+	backoff     time.Duration
+	nextAttempt time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewNetworkSink starts a NetworkSink dialing network/addr lazily on the
+// first flush. flushInterval must be positive, since it drives the
+// background flush ticker.
+func NewNetworkSink(network, addr string, enc Encoding, batchSize int, flushInterval, maxBackoff time.Duration) (*NetworkSink, error) {
+	if flushInterval <= 0 {
+		return nil, fmt.Errorf("logger: network sink flush interval must be positive, got %s", flushInterval)
+	}
+	s := &NetworkSink{
+		Network:       network,
+		Addr:          addr,
+		Enc:           enc,
+		BatchSize:     batchSize,
+		FlushInterval: flushInterval,
+		MaxBackoff:    maxBackoff,
+		backoff:       100 * time.Millisecond,
+		stop:          make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.flushLoop()
+	return s, nil
+}
+
+func (s *NetworkSink) flushLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			s.flush()
+			return
+		case <-ticker.C:
+			s.flush()
+		}
+	}
+}
+
+// Write implements Sink, appending the encoded record to the pending
+// batch and flushing immediately once BatchSize is reached.
+func (s *NetworkSink) Write(rec Record) error {
+	var b []byte
+	if s.Enc == JSONEncoding {
+		b = encodeJSON(rec)
+	} else {
+		b = encodeText(rec)
+	}
+
+	s.mu.Lock()
+	s.batch = append(s.batch, b)
+	full := s.BatchSize > 0 && len(s.batch) >= s.BatchSize
+	s.mu.Unlock()
+
+	if full {
+		return s.flush()
+	}
+	return nil
+}
+
+func (s *NetworkSink) flush() error {
+	s.mu.Lock()
+	batch := s.batch
+	s.batch = nil
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	conn, err := s.connection()
+	if err != nil {
+		return err
+	}
+	for _, b := range batch {
+		if _, err := conn.Write(b); err != nil {
+			s.mu.Lock()
+			s.conn = nil
+			s.mu.Unlock()
+			conn.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+// connection returns the current connection, redialing with exponential
+// backoff if it has dropped.
+func (s *NetworkSink) connection() (net.Conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		return s.conn, nil
+	}
+	if time.Now().Before(s.nextAttempt) {
+		return nil, fmt.Errorf("logger: network sink backing off reconnect to %s %s", s.Network, s.Addr)
+	}
+
+	conn, err := net.Dial(s.Network, s.Addr)
+	if err != nil {
+		s.nextAttempt = time.Now().Add(s.backoff)
+		s.backoff *= 2
+		if s.backoff > s.MaxBackoff {
+			s.backoff = s.MaxBackoff
+		}
+		return nil, fmt.Errorf("logger: dial %s %s: %w", s.Network, s.Addr, err)
+	}
+	s.backoff = 100 * time.Millisecond
+	s.conn = conn
+	return conn, nil
+}
+
+// Close implements Sink, flushing any pending batch and closing the
+// connection.
+func (s *NetworkSink) Close() error {
+	close(s.stop)
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return s.conn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNetworkSinkFlushesBatchOnBatchSize(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	s, err := NewNetworkSink("tcp", ln.Addr().String(), TextEncoding, 1, time.Hour, time.Second)
+	if err != nil {
+		t.Fatalf("NewNetworkSink: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.Write(Record{Severity: SeverityInfo, Message: "hello"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	select {
+	case line := <-received:
+		if !strings.Contains(line, "hello") {
+			t.Fatalf("expected received line to contain %q, got %q", "hello", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for a full batch to reach the listener")
+	}
+}
+
+func TestNetworkSinkBacksOffAfterDialFailure(t *testing.T) {
+	// Port 0 never accepts connections, so every dial fails.
+	s, err := NewNetworkSink("tcp", "127.0.0.1:0", TextEncoding, 1, time.Hour, time.Minute)
+	if err != nil {
+		t.Fatalf("NewNetworkSink: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.Write(Record{Severity: SeverityInfo, Message: "first"}); err == nil {
+		t.Fatalf("expected the first write to fail to dial")
+	}
+	if err := s.Write(Record{Severity: SeverityInfo, Message: "second"}); err == nil {
+		t.Fatalf("expected the immediate retry to be backed off rather than redialed")
+	}
+}
+
+func TestNewNetworkSinkRejectsNonPositiveFlushInterval(t *testing.T) {
+	if _, err := NewNetworkSink("tcp", "127.0.0.1:0", TextEncoding, 1, 0, time.Second); err == nil {
+		t.Fatalf("expected an error for a non-positive flush interval")
+	}
+}
@@ -0,0 +1,48 @@
+//go:build !windows && !plan9
+
+package logger
+
+import "log/syslog"
+
+// SyslogSink writes records to a syslog daemon via log/syslog, mapping
+// each Severity to the matching syslog priority.
+type SyslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogSink dials the syslog daemon (network and raddr empty for the
+// local daemon) and returns a Sink writing under tag with facility.
+func NewSyslogSink(network, raddr string, facility syslog.Priority, tag string) (*SyslogSink, error) {
+	w, err := syslog.Dial(network, raddr, facility|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{w: w}, nil
+}
+
+// Write implements Sink, routing each severity to the matching
+// log/syslog method. Records carrying fields are rendered through
+// TextEncoding first so they aren't dropped.
+func (s *SyslogSink) Write(rec Record) error {
+	msg := rec.Message
+	if len(rec.Fields) > 0 {
+		msg = string(encodeText(rec))
+	}
+	switch rec.Severity {
+	case SeverityInfo:
+		return s.w.Info(msg)
+	case SeverityWarning:
+		return s.w.Warning(msg)
+	case SeverityError:
+		return s.w.Err(msg)
+	case SeverityFatal:
+		return s.w.Crit(msg)
+	default:
+		return s.w.Info(msg)
+	}
+}
+
+// Close implements Sink.
+func (s *SyslogSink) Close() error {
+	return s.w.Close()
+}
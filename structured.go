@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encoding selects how a severity's records are rendered before being
+// written to its handle.
+type Encoding int
+
+const (
+	// TextEncoding renders records as "SEVERITY: date time file:line: msg key=value ...".
+	TextEncoding Encoding = iota
+	// JSONEncoding renders one JSON object per line, suitable for ingestion by log shippers.
+	JSONEncoding
+)
+
+const textTimeFormat = "2006/01/02 15:04:05"
+
+// Record is a single structured log record, built by Logger.output and
+// handed to the configured encoder.
+type Record struct {
+	Time     time.Time
+	Severity Severity
+	File     string
+	Line     int
+	Message  string
+	Fields   map[string]interface{}
+}
+
+// Entry is an immutable structured log record builder returned by
+// WithField/WithFields. Fields accumulate across calls, and the
+// top-level Info/Warning/Error/Fatal functions behave as a fieldless
+// Entry.
+type Entry struct {
+	fields map[string]interface{}
+}
+
+// WithField returns a new Entry carrying k=v.
+func WithField(k string, v interface{}) *Entry {
+	return (&Entry{}).WithField(k, v)
+}
+
+// WithFields returns a new Entry carrying fields.
+func WithFields(fields map[string]interface{}) *Entry {
+	return (&Entry{}).WithFields(fields)
+}
+
+// WithField returns a new Entry carrying e's fields plus k=v.
+func (e *Entry) WithField(k string, v interface{}) *Entry {
+	return e.WithFields(map[string]interface{}{k: v})
+}
+
+// WithFields returns a new Entry carrying e's fields merged with fields.
+func (e *Entry) WithFields(fields map[string]interface{}) *Entry {
+	merged := make(map[string]interface{}, len(e.fields)+len(fields))
+	for k, v := range e.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Entry{fields: merged}
+}
+
+// Info ...
+func (e *Entry) Info(v ...interface{}) {
+	defaultLogger.output(SeverityInfo, 0, e.fields, fmt.Sprint(v...))
+}
+
+// Infof ...
+func (e *Entry) Infof(format string, v ...interface{}) {
+	defaultLogger.output(SeverityInfo, 0, e.fields, fmt.Sprintf(format, v...))
+}
+
+// Warning ...
+func (e *Entry) Warning(v ...interface{}) {
+	defaultLogger.output(SeverityWarning, 0, e.fields, fmt.Sprint(v...))
+}
+
+// Warningf ...
+func (e *Entry) Warningf(format string, v ...interface{}) {
+	defaultLogger.output(SeverityWarning, 0, e.fields, fmt.Sprintf(format, v...))
+}
+
+// Error ...
+func (e *Entry) Error(v ...interface{}) {
+	defaultLogger.output(SeverityError, 0, e.fields, fmt.Sprint(v...))
+}
+
+// Errorf ...
+func (e *Entry) Errorf(format string, v ...interface{}) {
+	defaultLogger.output(SeverityError, 0, e.fields, fmt.Sprintf(format, v...))
+}
+
+// Fatal ...
+func (e *Entry) Fatal(v ...interface{}) {
+	defaultLogger.output(SeverityFatal, 0, e.fields, fmt.Sprint(v...))
+	defaultLogger.Flush()
+	defaultLogger.Close()
+	os.Exit(1)
+}
+
+// Fatalf ...
+func (e *Entry) Fatalf(format string, v ...interface{}) {
+	defaultLogger.output(SeverityFatal, 0, e.fields, fmt.Sprintf(format, v...))
+	defaultLogger.Flush()
+	defaultLogger.Close()
+	os.Exit(1)
+}
+
+func encodeText(r Record) []byte {
+	var b strings.Builder
+	b.WriteString(r.Severity.String())
+	b.WriteString(": ")
+	b.WriteString(r.Time.Format(textTimeFormat))
+	b.WriteByte(' ')
+	b.WriteString(r.File)
+	b.WriteByte(':')
+	b.WriteString(strconv.Itoa(r.Line))
+	b.WriteString(": ")
+	b.WriteString(r.Message)
+	for _, k := range sortedKeys(r.Fields) {
+		fmt.Fprintf(&b, " %s=%v", k, r.Fields[k])
+	}
+	b.WriteByte('\n')
+	return []byte(b.String())
+}
+
+func encodeJSON(r Record) []byte {
+	obj := make(map[string]interface{}, len(r.Fields)+4)
+	for k, v := range r.Fields {
+		obj[k] = v
+	}
+	obj["time"] = r.Time.Format(time.RFC3339Nano)
+	obj["severity"] = r.Severity.String()
+	obj["file"] = fmt.Sprintf("%s:%d", r.File, r.Line)
+	obj["message"] = r.Message
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return []byte(fmt.Sprintf("{\"severity\":\"ERROR\",\"message\":\"failed to encode log record: %v\"}\n", err))
+	}
+	return append(b, '\n')
+}
+
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEntryWithFieldIsImmutable(t *testing.T) {
+	base := WithField("a", 1)
+	child := base.WithField("b", 2)
+
+	if _, ok := base.fields["b"]; ok {
+		t.Fatalf("WithField must not mutate the receiver's fields")
+	}
+	if child.fields["a"] != 1 || child.fields["b"] != 2 {
+		t.Fatalf("child entry missing merged fields: %+v", child.fields)
+	}
+}
+
+func TestEncodeTextSortsFields(t *testing.T) {
+	rec := Record{
+		Severity: SeverityInfo,
+		File:     "f.go",
+		Line:     10,
+		Message:  "msg",
+		Fields:   map[string]interface{}{"b": 2, "a": 1},
+	}
+	out := string(encodeText(rec))
+	ai, bi := strings.Index(out, "a=1"), strings.Index(out, "b=2")
+	if ai < 0 || bi < 0 || ai > bi {
+		t.Fatalf("expected fields in sorted order a then b, got %q", out)
+	}
+}
+
+func TestEncodeJSONIncludesMessageAndFields(t *testing.T) {
+	rec := Record{
+		Severity: SeverityError,
+		File:     "f.go",
+		Line:     5,
+		Message:  "boom",
+		Fields:   map[string]interface{}{"k": "v"},
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(encodeJSON(rec), &obj); err != nil {
+		t.Fatalf("encodeJSON produced invalid JSON: %v", err)
+	}
+	if obj["message"] != "boom" || obj["k"] != "v" || obj["severity"] != "ERROR" {
+		t.Fatalf("unexpected JSON object: %+v", obj)
+	}
+}
@@ -0,0 +1,185 @@
+package logger
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level is the verbosity level for V logs, analogous to klog/glog's -v.
+type Level int32
+
+// Verbose is returned by V and lets Info/Infof/Infoln calls on it be
+// no-ops when the call site's effective verbosity is below the level
+// passed to V.
+type Verbose bool
+
+var (
+	verbosityLevel int32 // accessed atomically, see SetVerbosity
+
+	vmoduleMu   sync.Mutex
+	vmodulePats []modulePattern
+
+	// siteCache caches the effective verbosity for a call site, keyed by
+	// the PC of the V() call, so the fast path of a disabled V(n) call
+	// does no allocation beyond the runtime.Caller lookup.
+	siteCache sync.Map // map[uintptr]Level
+)
+
+type modulePattern struct {
+	pattern string
+	level   Level
+}
+
+func init() {
+	flag.Var(&verbosityFlag{}, "v", "log verbosity level for V logs")
+	flag.Var(&vmoduleFlag{}, "vmodule", "comma-separated list of pattern=N settings for file-filtered verbosity")
+}
+
+// V reports whether verbosity at the given level is enabled for the
+// caller's file. Leave V(n).Infof(...) calls in production code; turn
+// them on globally with SetVerbosity or for a single file with
+// SetVModule.
+func V(level Level) Verbose {
+	pc, file, _, ok := runtime.Caller(1)
+	if !ok {
+		return Verbose(level <= Level(atomic.LoadInt32(&verbosityLevel)))
+	}
+	if v, cached := siteCache.Load(pc); cached {
+		return Verbose(level <= v.(Level))
+	}
+	eff := Level(atomic.LoadInt32(&verbosityLevel))
+	if lvl, matched := vmoduleLevel(file); matched {
+		eff = lvl
+	}
+	siteCache.Store(pc, eff)
+	return Verbose(level <= eff)
+}
+
+// Info logs to the INFO log if v is true.
+func (v Verbose) Info(args ...interface{}) {
+	if v {
+		defaultLogger.output(SeverityInfo, 0, nil, fmt.Sprint(args...))
+	}
+}
+
+// Infof logs to the INFO log if v is true.
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v {
+		defaultLogger.output(SeverityInfo, 0, nil, fmt.Sprintf(format, args...))
+	}
+}
+
+// Infoln logs to the INFO log if v is true.
+func (v Verbose) Infoln(args ...interface{}) {
+	if v {
+		defaultLogger.output(SeverityInfo, 0, nil, fmt.Sprintln(args...))
+	}
+}
+
+// SetVerbosity sets the global V log verbosity level.
+func SetVerbosity(level int) {
+	atomic.StoreInt32(&verbosityLevel, int32(level))
+	clearSiteCache()
+}
+
+// SetVModule sets the per-file verbosity overrides from a comma-separated
+// list of pattern=N settings, e.g. "handlers/*=3,db.go=2". A pattern
+// without a "/" is matched against the call site's base file name;
+// a pattern containing "/" is matched against a path suffix of the same
+// number of components, both via filepath.Match.
+func SetVModule(spec string) error {
+	var pats []modulePattern
+	for _, part := range strings.Split(spec, ",") {
+		if part == "" {
+			continue
+		}
+		eq := strings.LastIndex(part, "=")
+		if eq < 0 {
+			return fmt.Errorf("logger: invalid vmodule setting %q", part)
+		}
+		level, err := strconv.Atoi(part[eq+1:])
+		if err != nil {
+			return fmt.Errorf("logger: invalid vmodule level in %q: %v", part, err)
+		}
+		pats = append(pats, modulePattern{pattern: part[:eq], level: Level(level)})
+	}
+
+	vmoduleMu.Lock()
+	vmodulePats = pats
+	vmoduleMu.Unlock()
+	clearSiteCache()
+	return nil
+}
+
+func vmoduleLevel(file string) (Level, bool) {
+	vmoduleMu.Lock()
+	pats := vmodulePats
+	vmoduleMu.Unlock()
+	for _, p := range pats {
+		if moduleMatches(file, p.pattern) {
+			return p.level, true
+		}
+	}
+	return 0, false
+}
+
+func moduleMatches(file, pattern string) bool {
+	if !strings.Contains(pattern, "/") {
+		matched, _ := filepath.Match(pattern, filepath.Base(file))
+		return matched
+	}
+	patParts := strings.Split(pattern, "/")
+	fileParts := strings.Split(filepath.ToSlash(file), "/")
+	if len(patParts) > len(fileParts) {
+		return false
+	}
+	suffix := strings.Join(fileParts[len(fileParts)-len(patParts):], "/")
+	matched, _ := filepath.Match(pattern, suffix)
+	return matched
+}
+
+func clearSiteCache() {
+	siteCache.Range(func(key, _ interface{}) bool {
+		siteCache.Delete(key)
+		return true
+	})
+}
+
+// verbosityFlag adapts SetVerbosity to flag.Var as "-v".
+type verbosityFlag struct{}
+
+func (f *verbosityFlag) String() string {
+	return strconv.Itoa(int(atomic.LoadInt32(&verbosityLevel)))
+}
+
+func (f *verbosityFlag) Set(s string) error {
+	level, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	SetVerbosity(level)
+	return nil
+}
+
+// vmoduleFlag adapts SetVModule to flag.Var as "-vmodule".
+type vmoduleFlag struct{}
+
+func (f *vmoduleFlag) String() string {
+	vmoduleMu.Lock()
+	defer vmoduleMu.Unlock()
+	parts := make([]string, len(vmodulePats))
+	for i, p := range vmodulePats {
+		parts[i] = fmt.Sprintf("%s=%d", p.pattern, p.level)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f *vmoduleFlag) Set(s string) error {
+	return SetVModule(s)
+}
@@ -0,0 +1,42 @@
+package logger
+
+import "testing"
+
+func TestVRespectsGlobalVerbosity(t *testing.T) {
+	defer SetVerbosity(0)
+
+	SetVerbosity(0)
+	if bool(V(1)) {
+		t.Fatalf("V(1) should be disabled at verbosity 0")
+	}
+
+	SetVerbosity(2)
+	if !bool(V(1)) {
+		t.Fatalf("V(1) should be enabled at verbosity 2")
+	}
+}
+
+func TestVModuleOverridesGlobalVerbosity(t *testing.T) {
+	defer SetVerbosity(0)
+	defer SetVModule("")
+
+	SetVerbosity(0)
+	if err := SetVModule("verbosity_test.go=3"); err != nil {
+		t.Fatalf("SetVModule: %v", err)
+	}
+	if !bool(V(2)) {
+		t.Fatalf("V(2) should be enabled via a vmodule override matching this file")
+	}
+}
+
+func TestSetVModuleRejectsMissingEquals(t *testing.T) {
+	if err := SetVModule("badspec"); err == nil {
+		t.Fatalf("expected an error for a vmodule entry without '='")
+	}
+}
+
+func TestSetVModuleRejectsNonNumericLevel(t *testing.T) {
+	if err := SetVModule("f.go=notanumber"); err == nil {
+		t.Fatalf("expected an error for a non-numeric vmodule level")
+	}
+}